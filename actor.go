@@ -0,0 +1,67 @@
+package main
+
+import "sync"
+
+// PostActor owns its views in a single goroutine; all access goes through
+// channels, so no mutex is needed.
+type PostActor struct {
+	views int
+	inc   chan struct{}
+	get   chan chan int
+	quit  chan struct{}
+	done  chan struct{}
+	stop  sync.Once
+}
+
+func NewPostActor() *PostActor {
+	return &PostActor{
+		inc:  make(chan struct{}),
+		get:  make(chan chan int),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+func (a *PostActor) Start() {
+	go a.loop()
+}
+
+// Stop shuts the actor down and waits for its goroutine to exit. It is
+// safe to call more than once.
+func (a *PostActor) Stop() {
+	a.stop.Do(func() { close(a.quit) })
+	<-a.done
+}
+
+// Inc is dropped once the actor has stopped.
+func (a *PostActor) Inc() {
+	select {
+	case a.inc <- struct{}{}:
+	case <-a.done:
+	}
+}
+
+// Get returns the final count once the actor has stopped.
+func (a *PostActor) Get() int {
+	reply := make(chan int, 1)
+	select {
+	case a.get <- reply:
+		return <-reply
+	case <-a.done:
+		return a.views
+	}
+}
+
+func (a *PostActor) loop() {
+	defer close(a.done)
+	for {
+		select {
+		case <-a.inc:
+			a.views++
+		case reply := <-a.get:
+			reply <- a.views
+		case <-a.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPostActorInterleaved(t *testing.T) {
+	a := NewPostActor()
+	a.Start()
+	defer a.Stop()
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := 0
+			for range 10 {
+				a.Inc()
+				v := a.Get()
+				if v < last {
+					t.Errorf("Get() went backwards: %d after %d", v, last)
+				}
+				last = v
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := a.Get(); got != 1000 {
+		t.Fatalf("Get() = %d, want 1000", got)
+	}
+}
+
+func TestPostActorAfterStop(t *testing.T) {
+	a := NewPostActor()
+	a.Start()
+	a.Inc()
+	a.Stop()
+	a.Stop()
+
+	a.Inc()
+	if got := a.Get(); got != 1 {
+		t.Fatalf("Get() after Stop = %d, want 1", got)
+	}
+}
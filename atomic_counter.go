@@ -0,0 +1,19 @@
+package main
+
+import "sync/atomic"
+
+type AtomicCounter struct {
+	n atomic.Int64
+}
+
+func (c *AtomicCounter) Inc() {
+	c.n.Add(1)
+}
+
+func (c *AtomicCounter) Add(delta int64) {
+	c.n.Add(delta)
+}
+
+func (c *AtomicCounter) Get() int64 {
+	return c.n.Load()
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicCounter(t *testing.T) {
+	var c AtomicCounter
+	var wg sync.WaitGroup
+
+	for range 1000 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Inc()
+		}()
+	}
+	wg.Wait()
+	c.Add(-500)
+
+	if got := c.Get(); got != 500 {
+		t.Fatalf("Get() = %d, want 500", got)
+	}
+}
+
+// Run with -cpu=1,4,8 to see where the atomic counter pulls ahead.
+func BenchmarkAtomicCounterInc(b *testing.B) {
+	var c AtomicCounter
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Inc()
+		}
+	})
+}
+
+func BenchmarkPostInc(b *testing.B) {
+	p := newPost(0, 0)
+	b.RunParallel(func(pb *testing.PB) {
+		var wg sync.WaitGroup
+		for pb.Next() {
+			wg.Add(1)
+			p.inc(&wg)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package main
+
+import "sync"
+
+// Barrier holds every Wait caller until Release, then lets them all go at
+// once. Releasing more than once is a no-op.
+type Barrier struct {
+	ch   chan struct{}
+	once sync.Once
+}
+
+func NewBarrier() *Barrier {
+	return &Barrier{ch: make(chan struct{})}
+}
+
+func (b *Barrier) Wait() {
+	<-b.ch
+}
+
+func (b *Barrier) Release() {
+	b.once.Do(func() { close(b.ch) })
+}
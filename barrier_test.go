@@ -0,0 +1,38 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBarrierHoldsUntilRelease(t *testing.T) {
+	b := NewBarrier()
+	p := newPost(0, 0)
+	var passed atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Wait()
+			passed.Add(1)
+			p.Inc()
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if n := passed.Load(); n != 0 {
+		t.Fatalf("%d goroutines passed Wait before Release", n)
+	}
+
+	b.Release()
+	b.Release()
+	wg.Wait()
+
+	if got := p.Get(); got != 20 {
+		t.Fatalf("Get() = %d, want 20", got)
+	}
+}
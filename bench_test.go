@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+// mutexPost is post's view counter guarded by a plain Mutex instead of an
+// RWMutex, kept only for comparison.
+type mutexPost struct {
+	views int
+	mu    sync.Mutex
+}
+
+func (p *mutexPost) Inc() {
+	p.mu.Lock()
+	p.views++
+	p.mu.Unlock()
+}
+
+func (p *mutexPost) Get() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.views
+}
+
+// rwMutexPost is mutexPost with an RWMutex, so Get can share the lock.
+type rwMutexPost struct {
+	views int
+	mu    sync.RWMutex
+}
+
+func (p *rwMutexPost) Inc() {
+	p.mu.Lock()
+	p.views++
+	p.mu.Unlock()
+}
+
+func (p *rwMutexPost) Get() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.views
+}
+
+type incGetter interface {
+	Inc()
+	Get() int
+}
+
+// benchMixed hammers c from GOMAXPROCS goroutines, each picking a read with
+// probability readPct/100 and a write otherwise.
+func benchMixed(b *testing.B, c incGetter, readPct int) {
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+		for pb.Next() {
+			if r.IntN(100) < readPct {
+				c.Get()
+			} else {
+				c.Inc()
+			}
+		}
+	})
+}
+
+// Run with -cpu=1,4,8 to see where the RWMutex pays off.
+func BenchmarkMixed(b *testing.B) {
+	for _, readPct := range []int{90, 50, 10} {
+		ratio := fmt.Sprintf("%d:%d", readPct, 100-readPct)
+		b.Run(ratio+"/RWMutex", func(b *testing.B) {
+			benchMixed(b, &rwMutexPost{}, readPct)
+		})
+		b.Run(ratio+"/Mutex", func(b *testing.B) {
+			benchMixed(b, &mutexPost{}, readPct)
+		})
+	}
+}
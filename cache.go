@@ -0,0 +1,48 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type readCache struct {
+	v    int
+	at   time.Time
+	call *cacheCall
+	mu   sync.Mutex
+}
+
+type cacheCall struct {
+	v    int
+	done chan struct{}
+}
+
+// CachedGet returns the value from the last read if it is younger than ttl.
+// On a miss, concurrent callers share a single underlying get.
+func (p *post) CachedGet(ttl time.Duration) int {
+	c := &p.cache
+
+	c.mu.Lock()
+	if !c.at.IsZero() && time.Since(c.at) < ttl {
+		v := c.v
+		c.mu.Unlock()
+		return v
+	}
+	if call := c.call; call != nil {
+		c.mu.Unlock()
+		<-call.done
+		return call.v
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.call = call
+	c.mu.Unlock()
+
+	call.v = p.get()
+
+	c.mu.Lock()
+	c.v, c.at, c.call = call.v, time.Now(), nil
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.v
+}
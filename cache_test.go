@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type getCounter struct {
+	gets atomic.Int32
+}
+
+func (l *getCounter) Lock(op string, _ uint64) {
+	if op == "get" {
+		l.gets.Add(1)
+	}
+}
+
+func (l *getCounter) Unlock(string, uint64) {}
+
+func TestCachedGetSingleFlight(t *testing.T) {
+	p := newPost(5, 20*time.Millisecond)
+	reads := &getCounter{}
+	p.logger = reads
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := p.CachedGet(time.Minute); got != 5 {
+				t.Errorf("CachedGet() = %d, want 5", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := reads.gets.Load(); n != 1 {
+		t.Fatalf("%d underlying reads, want 1", n)
+	}
+}
+
+func TestCachedGetExpires(t *testing.T) {
+	p := newPost(1, 0)
+	reads := &getCounter{}
+	p.logger = reads
+
+	p.CachedGet(time.Minute)
+	p.Inc()
+	if got := p.CachedGet(time.Minute); got != 1 {
+		t.Fatalf("CachedGet() within ttl = %d, want cached 1", got)
+	}
+	if got := p.CachedGet(0); got != 2 {
+		t.Fatalf("CachedGet() after ttl = %d, want 2", got)
+	}
+	if n := reads.gets.Load(); n != 2 {
+		t.Fatalf("%d underlying reads, want 2", n)
+	}
+}
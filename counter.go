@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const lockRetryInterval = time.Millisecond
+
+var ErrOverflow = errors.New("counter would overflow")
+
+type Counter struct {
+	n    int64
+	mu   sync.RWMutex
+	last atomic.Int64 // n as of the last write, for GetOrStale
+
+	cond     *sync.Cond
+	condOnce sync.Once
+
+	trackContention atomic.Bool
+	acquisitions    atomic.Int64
+	waits           atomic.Int64
+}
+
+type ContentionStats struct {
+	Acquisitions int64
+	Waits        int64
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) Add(delta int) {
+	c.lock()
+	c.n = satAdd(c.n, int64(delta))
+	c.changed()
+	c.mu.Unlock()
+}
+
+func (c *Counter) Get() int {
+	c.rlock()
+	defer c.mu.RUnlock()
+	return int(c.n)
+}
+
+func (c *Counter) Reset() {
+	c.lock()
+	c.resetLocked()
+	c.mu.Unlock()
+}
+
+func (c *Counter) Dec() bool {
+	c.lock()
+	defer c.mu.Unlock()
+	return c.decLocked()
+}
+
+func (c *Counter) CompareAndInc(expected int) (newValue int, ok bool) {
+	c.lock()
+	defer c.mu.Unlock()
+	return c.casLocked(expected)
+}
+
+// TryAdd applies delta only if the result fits in an int64, and reports
+// ErrOverflow otherwise. Add and Inc saturate instead.
+func (c *Counter) TryAdd(delta int64) (int64, error) {
+	c.lock()
+	defer c.mu.Unlock()
+	return c.tryAddLocked(delta)
+}
+
+// The *Locked helpers below must be called with c.mu held for writing. They
+// are shared with post, which wraps them to record history and notify.
+
+func (c *Counter) resetLocked() {
+	c.n = 0
+	c.changed()
+}
+
+func (c *Counter) decLocked() bool {
+	if c.n <= 0 {
+		return false
+	}
+	c.n--
+	c.changed()
+	return true
+}
+
+func (c *Counter) casLocked(expected int) (int, bool) {
+	if c.n != int64(expected) || c.n == math.MaxInt64 {
+		return int(c.n), false
+	}
+	c.n++
+	c.changed()
+	return int(c.n), true
+}
+
+func (c *Counter) tryAddLocked(delta int64) (int64, error) {
+	if (delta > 0 && c.n > math.MaxInt64-delta) || (delta < 0 && c.n < math.MinInt64-delta) {
+		return c.n, ErrOverflow
+	}
+	c.n += delta
+	c.changed()
+	return c.n, nil
+}
+
+// GetOrStale returns the current value with fresh set if the read lock is
+// free. Otherwise it returns, without blocking, the value as of the last
+// write. Only writers update that cache, so readers never contend on it.
+func (c *Counter) GetOrStale() (value int, fresh bool) {
+	if !c.tryRLock() {
+		return int(c.last.Load()), false
+	}
+	defer c.mu.RUnlock()
+	return int(c.n), true
+}
+
+// acquireContext polls try until it succeeds or ctx is done.
+func acquireContext(ctx context.Context, try func() bool) error {
+	if try() {
+		return nil
+	}
+
+	t := time.NewTicker(lockRetryInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if try() {
+				return nil
+			}
+		}
+	}
+}
+
+func satAdd(a, b int64) int64 {
+	switch {
+	case b > 0 && a > math.MaxInt64-b:
+		return math.MaxInt64
+	case b < 0 && a < math.MinInt64-b:
+		return math.MinInt64
+	}
+	return a + b
+}
+
+// WaitFor blocks until the value is at least target.
+func (c *Counter) WaitFor(target int) {
+	cond := c.waiters()
+	c.rlock()
+	defer c.mu.RUnlock()
+	for c.n < int64(target) {
+		cond.Wait()
+	}
+}
+
+func (c *Counter) waiters() *sync.Cond {
+	c.condOnce.Do(func() { c.cond = sync.NewCond((*trackedRLocker)(c)) })
+	return c.cond
+}
+
+// changed must be called with c.mu held for writing after n is modified.
+func (c *Counter) changed() {
+	c.observe()
+	c.waiters().Broadcast()
+}
+
+// observe must be called with c.mu held.
+func (c *Counter) observe() {
+	c.last.Store(c.n)
+}
+
+// SetTrackContention turns contention tracking on or off. While off the
+// lock paths skip all bookkeeping.
+func (c *Counter) SetTrackContention(on bool) {
+	c.trackContention.Store(on)
+}
+
+// ContentionStats reports how many lock acquisitions happened and how many
+// of them found the lock already held; a failed try-lock also counts as a
+// wait. Both stay zero unless tracking is on.
+func (c *Counter) ContentionStats() ContentionStats {
+	return ContentionStats{
+		Acquisitions: c.acquisitions.Load(),
+		Waits:        c.waits.Load(),
+	}
+}
+
+func (c *Counter) lock() {
+	if !c.trackContention.Load() {
+		c.mu.Lock()
+		return
+	}
+	c.acquisitions.Add(1)
+	if !c.mu.TryLock() {
+		c.waits.Add(1)
+		c.mu.Lock()
+	}
+}
+
+func (c *Counter) rlock() {
+	if !c.trackContention.Load() {
+		c.mu.RLock()
+		return
+	}
+	c.acquisitions.Add(1)
+	if !c.mu.TryRLock() {
+		c.waits.Add(1)
+		c.mu.RLock()
+	}
+}
+
+func (c *Counter) tryLock() bool {
+	ok := c.mu.TryLock()
+	c.countTry(ok)
+	return ok
+}
+
+func (c *Counter) tryRLock() bool {
+	ok := c.mu.TryRLock()
+	c.countTry(ok)
+	return ok
+}
+
+func (c *Counter) countTry(ok bool) {
+	if !c.trackContention.Load() {
+		return
+	}
+	if ok {
+		c.acquisitions.Add(1)
+	} else {
+		c.waits.Add(1)
+	}
+}
+
+// trackedRLocker lets sync.Cond re-acquire the read lock through rlock, so
+// WaitFor wake-ups are counted too.
+type trackedRLocker Counter
+
+func (l *trackedRLocker) Lock()   { (*Counter)(l).rlock() }
+func (l *trackedRLocker) Unlock() { l.mu.RUnlock() }
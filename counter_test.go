@@ -0,0 +1,226 @@
+package main
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCounterConcurrentInc(t *testing.T) {
+	var c Counter
+	var wg sync.WaitGroup
+
+	for range 1000 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Inc()
+		}()
+	}
+
+	wg.Wait()
+	if got := c.Get(); got != 1000 {
+		t.Fatalf("Get() = %d, want 1000", got)
+	}
+}
+
+func TestCounterAddReset(t *testing.T) {
+	var c Counter
+	c.Add(5)
+	c.Add(-2)
+	if got := c.Get(); got != 3 {
+		t.Fatalf("Get() = %d, want 3", got)
+	}
+
+	c.Reset()
+	if got := c.Get(); got != 0 {
+		t.Fatalf("Get() after Reset = %d, want 0", got)
+	}
+}
+
+func TestDecNeverNegative(t *testing.T) {
+	var c Counter
+	c.Add(10)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	applied := 0
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.Dec() {
+				mu.Lock()
+				applied++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if applied != 10 {
+		t.Fatalf("%d decrements applied, want 10", applied)
+	}
+	if got := c.Get(); got != 0 {
+		t.Fatalf("Get() = %d, want 0", got)
+	}
+}
+
+func TestContentionStats(t *testing.T) {
+	var c Counter
+	c.Inc()
+	if s := c.ContentionStats(); s != (ContentionStats{}) {
+		t.Fatalf("stats with tracking off = %+v, want zero", s)
+	}
+
+	c.SetTrackContention(true)
+
+	// Hold the lock so every writer has to wait for it.
+	c.mu.Lock()
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Inc()
+		}()
+	}
+	for c.ContentionStats().Waits < 50 {
+		runtime.Gosched()
+	}
+	c.mu.Unlock()
+	wg.Wait()
+
+	s := c.ContentionStats()
+	if s.Acquisitions != 50 || s.Waits != 50 {
+		t.Fatalf("stats = %+v, want 50 acquisitions and 50 waits", s)
+	}
+}
+
+func TestContentionStatsTryPaths(t *testing.T) {
+	p := newPost(0, 0)
+	p.SetTrackContention(true)
+
+	p.mu.Lock()
+	if p.TryInc() {
+		t.Fatal("TryInc succeeded while the lock was held")
+	}
+	if _, fresh := p.GetOrStale(); fresh {
+		t.Fatal("GetOrStale fresh while the lock was held")
+	}
+	p.mu.Unlock()
+
+	if !p.TryInc() {
+		t.Fatal("TryInc failed on a free lock")
+	}
+	if s := p.ContentionStats(); s.Acquisitions != 1 || s.Waits != 2 {
+		t.Fatalf("stats = %+v, want 1 acquisition and 2 waits", s)
+	}
+}
+
+func TestCompareAndIncOneWinner(t *testing.T) {
+	for range 100 {
+		var c Counter
+		var wg sync.WaitGroup
+		results := make([]bool, 2)
+
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, results[i] = c.CompareAndInc(0)
+			}()
+		}
+		wg.Wait()
+
+		if results[0] == results[1] {
+			t.Fatalf("CompareAndInc results = %v, want exactly one true", results)
+		}
+		if got := c.Get(); got != 1 {
+			t.Fatalf("Get() = %d, want 1", got)
+		}
+	}
+}
+
+func TestGetOrStale(t *testing.T) {
+	var c Counter
+	c.Add(3)
+
+	if v, fresh := c.GetOrStale(); v != 3 || !fresh {
+		t.Fatalf("GetOrStale() = %d, %v; want 3, true", v, fresh)
+	}
+
+	c.mu.Lock()
+	c.n = 99 // a write in progress, not yet published
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if v, fresh := c.GetOrStale(); v != 3 || fresh {
+			t.Errorf("GetOrStale() under write lock = %d, %v; want 3, false", v, fresh)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("GetOrStale blocked on the write lock")
+	}
+	c.n = 3
+	c.mu.Unlock()
+}
+
+func TestCounterOverflow(t *testing.T) {
+	var c Counter
+	c.Add(math.MaxInt64 - 1)
+	c.Inc()
+	c.Inc()
+	if got := c.Get(); got != math.MaxInt64 {
+		t.Fatalf("Get() = %d, want saturated MaxInt64", got)
+	}
+
+	if v, err := c.TryAdd(1); err != ErrOverflow || v != math.MaxInt64 {
+		t.Fatalf("TryAdd(1) = %d, %v; want MaxInt64, ErrOverflow", v, err)
+	}
+	if v, err := c.TryAdd(-1); err != nil || v != math.MaxInt64-1 {
+		t.Fatalf("TryAdd(-1) = %d, %v; want MaxInt64-1, nil", v, err)
+	}
+
+	p := newPost(math.MaxInt64, 0)
+	p.Inc()
+	if got := p.Get(); got != math.MaxInt64 {
+		t.Fatalf("post Get() = %d, want saturated MaxInt64", got)
+	}
+}
+
+func TestWaitForThresholds(t *testing.T) {
+	p := newPost(0, 0)
+	targets := []int{5, 10, 15}
+	done := make([]chan struct{}, len(targets))
+	for i, target := range targets {
+		done[i] = make(chan struct{})
+		go func() {
+			p.WaitFor(target)
+			close(done[i])
+		}()
+	}
+
+	for v := 1; v <= 15; v++ {
+		p.Inc()
+		for i, target := range targets {
+			if target <= v {
+				select {
+				case <-done[i]:
+				case <-time.After(time.Second):
+					t.Fatalf("WaitFor(%d) still blocked at views=%d", target, v)
+				}
+				continue
+			}
+			select {
+			case <-done[i]:
+				t.Fatalf("WaitFor(%d) returned at views=%d", target, v)
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer coalesces Record calls and applies them to a post in batches,
+// once flushEvery events are pending or maxWait has passed since the first
+// pending one. The pending count lives in the flushing goroutine only;
+// events reach it through a channel buffered to flushEvery, so Record
+// rarely has to wait for a handoff.
+type Debouncer struct {
+	p          *post
+	maxWait    time.Duration
+	flushEvery int
+
+	events chan struct{}
+	quit   chan struct{}
+	done   chan struct{}
+	stop   sync.Once
+}
+
+func NewDebouncer(p *post, maxWait time.Duration, flushEvery int) *Debouncer {
+	flushEvery = max(flushEvery, 1)
+	d := &Debouncer{
+		p:          p,
+		maxWait:    maxWait,
+		flushEvery: flushEvery,
+		events:     make(chan struct{}, flushEvery),
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go d.loop()
+	return d
+}
+
+func (d *Debouncer) Record() {
+	d.events <- struct{}{}
+}
+
+// Stop flushes whatever is pending and waits for the flusher to exit.
+// It is safe to call more than once; Record must not be called after Stop.
+func (d *Debouncer) Stop() {
+	d.stop.Do(func() { close(d.quit) })
+	<-d.done
+}
+
+func (d *Debouncer) loop() {
+	defer close(d.done)
+
+	var (
+		pending int
+		timer   *time.Timer
+		timeout <-chan time.Time
+	)
+	flush := func() {
+		if pending > 0 {
+			d.p.Add(pending)
+			pending = 0
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		timeout = nil
+	}
+
+	for {
+		select {
+		case <-d.events:
+			pending++
+			if pending == 1 {
+				timer = time.NewTimer(d.maxWait)
+				timeout = timer.C
+			}
+			if pending >= d.flushEvery {
+				flush()
+			}
+		case <-timeout:
+			flush()
+		case <-d.quit:
+			for len(d.events) > 0 {
+				<-d.events
+				pending++
+			}
+			flush()
+			return
+		}
+	}
+}
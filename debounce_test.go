@@ -0,0 +1,48 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDebouncerStopFlushesAll(t *testing.T) {
+	p := newPost(0, 0)
+	d := NewDebouncer(p, time.Hour, 64)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				d.Record()
+			}
+		}()
+	}
+	wg.Wait()
+	d.Stop()
+	d.Stop()
+
+	if got := p.Get(); got != 1000 {
+		t.Fatalf("Get() after Stop = %d, want 1000", got)
+	}
+}
+
+func TestDebouncerFlushesAfterMaxWait(t *testing.T) {
+	p := newPost(0, 0)
+	d := NewDebouncer(p, 10*time.Millisecond, 1000)
+	defer d.Stop()
+
+	for range 5 {
+		d.Record()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for p.Get() != 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Get() = %d after 1s, want 5 flushed by maxWait", p.Get())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import "sync"
+
+const fairQueueSize = 128
+
+// FairPost applies increments strictly in the order they were submitted.
+// Requests queue on a channel and a single dispatcher goroutine services
+// them, which sync.Mutex alone does not guarantee. The post is kept in a
+// named field so nothing can increment it around the queue.
+type FairPost struct {
+	p    *post
+	reqs chan fairReq
+	quit chan struct{}
+	done chan struct{}
+	stop sync.Once
+}
+
+type fairReq struct {
+	onApply func(newValue int)
+	ack     chan int
+}
+
+func NewFairPost() *FairPost {
+	f := &FairPost{
+		p:    newPost(0, 0),
+		reqs: make(chan fairReq, fairQueueSize),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go f.dispatch()
+	return f
+}
+
+// Submit enqueues an increment. Once it is applied, onApply (if not nil) is
+// called from the dispatcher with the new value, and the returned channel
+// receives that value.
+func (f *FairPost) Submit(onApply func(newValue int)) <-chan int {
+	ack := make(chan int, 1)
+	f.reqs <- fairReq{onApply: onApply, ack: ack}
+	return ack
+}
+
+// Inc submits an increment and waits for it to be applied.
+func (f *FairPost) Inc() {
+	<-f.Submit(nil)
+}
+
+func (f *FairPost) Get() int {
+	return f.p.Get()
+}
+
+func (f *FairPost) View() PostView {
+	return f.p.View()
+}
+
+// Stop applies any requests already queued and stops the dispatcher.
+// It is safe to call more than once; Submit must not be called after Stop.
+func (f *FairPost) Stop() {
+	f.stop.Do(func() { close(f.quit) })
+	<-f.done
+}
+
+func (f *FairPost) dispatch() {
+	defer close(f.done)
+	for {
+		select {
+		case req := <-f.reqs:
+			f.apply(req)
+		case <-f.quit:
+			for {
+				select {
+				case req := <-f.reqs:
+					f.apply(req)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (f *FairPost) apply(req fairReq) {
+	v := f.p.add(1)
+	if req.onApply != nil {
+		req.onApply(v)
+	}
+	req.ack <- v
+}
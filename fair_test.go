@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestFairPostAppliesInOrder(t *testing.T) {
+	f := NewFairPost()
+
+	// onApply runs on the dispatcher goroutine, so order needs no lock;
+	// Stop orders these writes before the reads below.
+	var order []int
+	acks := make([]<-chan int, 100)
+	for i := range acks {
+		acks[i] = f.Submit(func(int) { order = append(order, i) })
+	}
+	for i, ack := range acks {
+		if v := <-ack; v != i+1 {
+			t.Fatalf("request %d applied as value %d, want %d", i, v, i+1)
+		}
+	}
+	f.Inc()
+	f.Stop()
+	f.Stop()
+
+	if len(order) != 100 {
+		t.Fatalf("%d callbacks, want 100", len(order))
+	}
+	for i, id := range order {
+		if id != i {
+			t.Fatalf("request %d applied at position %d", id, i)
+		}
+	}
+	if got := f.Get(); got != 101 {
+		t.Fatalf("Get() = %d, want 101", got)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// FanIn merges channels into one; the output closes once every input has.
+// The caller must drain the output, or the forwarding goroutines block on
+// it forever. Use FanInContext to be able to walk away.
+func FanIn(channels ...<-chan string) <-chan string {
+	return FanInContext(context.Background(), channels...)
+}
+
+// FanInContext is FanIn that also stops forwarding once ctx is done, so a
+// caller that stops reading can cancel ctx instead of draining the output.
+// The output still closes once every forwarding goroutine has returned.
+func FanInContext(ctx context.Context, channels ...<-chan string) <-chan string {
+	var wg sync.WaitGroup
+	out := make(chan string)
+
+	for _, ch := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case id, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case out <- id:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFanInMergesAll(t *testing.T) {
+	lengths := []int{3, 100, 17}
+	inputs := make([]<-chan string, len(lengths))
+	for i, n := range lengths {
+		ch := make(chan string)
+		inputs[i] = ch
+		go func() {
+			defer close(ch)
+			for j := range n {
+				ch <- fmt.Sprintf("%d-%d", i, j)
+			}
+		}()
+	}
+
+	seen := make(map[string]int)
+	for id := range FanIn(inputs...) {
+		seen[id]++
+	}
+
+	if len(seen) != 120 {
+		t.Fatalf("merged %d distinct events, want 120", len(seen))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Fatalf("event %q seen %d times", id, n)
+		}
+	}
+}
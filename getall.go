@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// GetAll reads every post concurrently. Results line up with posts by
+// index; the first error cancels the remaining reads and is returned.
+func GetAll(ctx context.Context, posts []*post) ([]int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	results := make([]int, len(posts))
+
+	for i, p := range posts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := p.GetContext(ctx)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = v
+		}()
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetAllKeepsOrder(t *testing.T) {
+	// Later posts finish first, so results arrive out of order.
+	posts := []*post{
+		newPost(10, 40*time.Millisecond),
+		newPost(20, 20*time.Millisecond),
+		newPost(30, 0),
+	}
+
+	got, err := GetAll(context.Background(), posts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{10, 20, 30}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("GetAll() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGetAllCancelsOnError(t *testing.T) {
+	posts := []*post{
+		newPost(1, 0),
+		newPost(2, time.Second),
+		newPost(3, time.Second),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := GetAll(ctx, posts); err != context.DeadlineExceeded {
+		t.Fatalf("GetAll() error = %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("GetAll() returned after %v, want ~20ms", d)
+	}
+}
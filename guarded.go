@@ -0,0 +1,27 @@
+package main
+
+import "sync"
+
+type Guarded[T any] struct {
+	v  T
+	mu sync.RWMutex
+}
+
+func (g *Guarded[T]) Load() T {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.v
+}
+
+func (g *Guarded[T]) Store(v T) {
+	g.mu.Lock()
+	g.v = v
+	g.mu.Unlock()
+}
+
+// Update applies fn inside the write lock, so the read-modify-write is atomic.
+func (g *Guarded[T]) Update(fn func(old T) T) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.v = fn(g.v)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestGuardedIntUpdate(t *testing.T) {
+	var g Guarded[int]
+	var wg sync.WaitGroup
+
+	for range 1000 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Update(func(old int) int { return old + 1 })
+		}()
+	}
+	wg.Wait()
+
+	if got := g.Load(); got != 1000 {
+		t.Fatalf("Load() = %d, want 1000", got)
+	}
+}
+
+func TestGuardedSliceUpdate(t *testing.T) {
+	var g Guarded[[]string]
+	var wg sync.WaitGroup
+
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Update(func(old []string) []string { return append(old, fmt.Sprint(i)) })
+		}()
+	}
+	wg.Wait()
+
+	got := g.Load()
+	if len(got) != 100 {
+		t.Fatalf("len(Load()) = %d, want 100", len(got))
+	}
+	seen := make(map[string]bool)
+	for _, s := range got {
+		if seen[s] {
+			t.Fatalf("%q appended twice", s)
+		}
+		seen[s] = true
+	}
+
+	g.Store(nil)
+	if got := g.Load(); got != nil {
+		t.Fatalf("Load() after Store(nil) = %v", got)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import "time"
+
+const historySize = 64
+
+type Sample struct {
+	Views int
+	At    time.Time
+}
+
+// history is a fixed-size ring of the most recent samples. It has no lock
+// of its own; the owning post's lock guards it.
+type history struct {
+	samples [historySize]Sample
+	next    int
+	full    bool
+}
+
+func (h *history) record(views int) {
+	h.samples[h.next] = Sample{Views: views, At: time.Now()}
+	h.next = (h.next + 1) % historySize
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+func (h *history) snapshot() []Sample {
+	if !h.full {
+		return append([]Sample(nil), h.samples[:h.next]...)
+	}
+	out := make([]Sample, 0, historySize)
+	out = append(out, h.samples[h.next:]...)
+	return append(out, h.samples[:h.next]...)
+}
+
+// History returns the most recent samples, oldest first.
+func (p *post) History() []Sample {
+	p.rlock()
+	defer p.mu.RUnlock()
+	return p.history.snapshot()
+}
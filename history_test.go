@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestHistoryKeepsMostRecent(t *testing.T) {
+	p := newPost(0, 0)
+	for range historySize + 10 {
+		p.Inc()
+	}
+
+	h := p.History()
+	if len(h) != historySize {
+		t.Fatalf("len(History()) = %d, want %d", len(h), historySize)
+	}
+	for i, s := range h {
+		if want := 11 + i; s.Views != want {
+			t.Fatalf("History()[%d].Views = %d, want %d", i, s.Views, want)
+		}
+		if i > 0 && s.At.Before(h[i-1].At) {
+			t.Fatalf("History()[%d] is older than the sample before it", i)
+		}
+	}
+}
+
+func TestHistoryPartial(t *testing.T) {
+	p := newPost(0, 0)
+	p.Inc()
+	p.Inc()
+
+	h := p.History()
+	if len(h) != 2 || h[0].Views != 1 || h[1].Views != 2 {
+		t.Fatalf("History() = %+v, want views 1, 2", h)
+	}
+}
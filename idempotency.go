@@ -0,0 +1,95 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const idempotencyKeyLimit = 10000
+
+// keySet remembers recently seen keys. It holds at most limit keys
+// (idempotencyKeyLimit if zero), dropping the oldest first, and forgets keys
+// older than ttl when ttl is set.
+type keySet struct {
+	limit int
+	ttl   time.Duration
+
+	seen  map[string]struct{}
+	order []keyEntry // insertion order, oldest at head
+	head  int
+	mu    sync.Mutex
+}
+
+type keyEntry struct {
+	key string
+	at  time.Time
+}
+
+func (s *keySet) configure(limit int, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit, s.ttl = limit, ttl
+	for len(s.seen) > s.maxKeys() {
+		s.evict()
+	}
+}
+
+func (s *keySet) maxKeys() int {
+	if s.limit <= 0 {
+		return idempotencyKeyLimit
+	}
+	return s.limit
+}
+
+// add records key and reports whether it was new.
+func (s *keySet) add(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.ttl > 0 {
+		for s.head < len(s.order) && now.Sub(s.order[s.head].at) >= s.ttl {
+			s.evict()
+		}
+	}
+	if _, ok := s.seen[key]; ok {
+		return false
+	}
+
+	if s.seen == nil {
+		s.seen = make(map[string]struct{})
+	}
+	for len(s.seen) >= s.maxKeys() {
+		s.evict()
+	}
+	s.seen[key] = struct{}{}
+	s.order = append(s.order, keyEntry{key: key, at: now})
+	return true
+}
+
+func (s *keySet) evict() {
+	delete(s.seen, s.order[s.head].key)
+	s.order[s.head] = keyEntry{}
+	s.head++
+	if s.head > len(s.order)/2 {
+		s.order = append(s.order[:0], s.order[s.head:]...)
+		s.head = 0
+	}
+}
+
+// SetIdempotency bounds the keys IncOnce remembers: at most limit keys
+// (idempotencyKeyLimit if limit <= 0), each for at most ttl (forever if
+// ttl <= 0).
+func (p *post) SetIdempotency(limit int, ttl time.Duration) {
+	p.seen.configure(limit, ttl)
+}
+
+// IncOnce increments only the first time key is seen and reports whether it
+// did. Keys are forgotten per SetIdempotency.
+func (p *post) IncOnce(key string) bool {
+	if !p.seen.add(key) {
+		return false
+	}
+	p.Inc()
+	return true
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIncOnceSameKey(t *testing.T) {
+	p := newPost(0, 0)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	applied := 0
+
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if p.IncOnce("view-1") {
+				mu.Lock()
+				applied++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if applied != 1 {
+		t.Fatalf("IncOnce reported %d increments, want 1", applied)
+	}
+	if got := p.Get(); got != 1 {
+		t.Fatalf("Get() = %d, want 1", got)
+	}
+}
+
+func TestIncOnceTTL(t *testing.T) {
+	p := newPost(0, 0)
+	p.SetIdempotency(0, 20*time.Millisecond)
+
+	if !p.IncOnce("k") || p.IncOnce("k") {
+		t.Fatal("key counted other than once within its ttl")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !p.IncOnce("k") {
+		t.Fatal("key not counted again after its ttl expired")
+	}
+	if got := p.Get(); got != 2 {
+		t.Fatalf("Get() = %d, want 2", got)
+	}
+}
+
+func TestIncOnceLimitEvictsOldest(t *testing.T) {
+	p := newPost(0, 0)
+	p.SetIdempotency(3, 0)
+
+	for i := range 4 {
+		p.IncOnce(fmt.Sprint(i))
+	}
+
+	// "0" was the oldest and has been evicted; "1".."3" are still known.
+	for _, key := range []string{"1", "2", "3"} {
+		if p.IncOnce(key) {
+			t.Fatalf("key %q counted again while still within the limit", key)
+		}
+	}
+	if !p.IncOnce("0") {
+		t.Fatal("evicted key 0 was not counted again")
+	}
+}
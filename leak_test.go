@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// settle polls until the goroutine count drops back to want or a short
+// deadline passes, and returns the last count seen.
+func settle(want int) int {
+	deadline := time.Now().Add(time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= want || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func assertNoLeaks(t *testing.T, fn func()) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	fn()
+	if after := settle(before); after > before {
+		t.Fatalf("goroutines leaked: %d before, %d after", before, after)
+	}
+}
+
+func TestGetAsyncNoLeak(t *testing.T) {
+	assertNoLeaks(t, func() {
+		<-newPost(1, time.Millisecond).GetAsync()
+	})
+	// An unread result must not pin the goroutine either.
+	assertNoLeaks(t, func() {
+		newPost(1, 0).GetAsync()
+	})
+}
+
+func TestViewWorkerPoolNoLeak(t *testing.T) {
+	assertNoLeaks(t, func() {
+		ids := make(chan string)
+		done := NewViewWorkerPool(&PostStore{}, 8).Run(ids)
+		for range 100 {
+			ids <- "a"
+		}
+		close(ids)
+		<-done
+	})
+}
+
+func TestFanInNoLeak(t *testing.T) {
+	assertNoLeaks(t, func() {
+		a, b := make(chan string), make(chan string)
+		go func() {
+			a <- "x"
+			b <- "y"
+			close(a)
+			close(b)
+		}()
+		for range FanIn(a, b) {
+		}
+	})
+}
+
+func TestFanInContextAbandonedNoLeak(t *testing.T) {
+	assertNoLeaks(t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		a, b := make(chan string, 2), make(chan string) // b never sends or closes
+		a <- "x"
+		a <- "y"
+		close(a)
+		<-FanInContext(ctx, a, b) // stop reading with an event still pending
+		cancel()
+	})
+}
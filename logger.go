@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// Logger traces lock activity on a post. Lock is called once the lock for
+// op is held and Unlock once it has been released.
+type Logger interface {
+	Lock(op string, goid uint64)
+	Unlock(op string, goid uint64)
+}
+
+func (p *post) traceLock(op string) {
+	if p.logger != nil {
+		p.logger.Lock(op, goid())
+	}
+}
+
+func (p *post) traceUnlock(op string) {
+	if p.logger != nil {
+		p.logger.Unlock(op, goid())
+	}
+}
+
+// goid parses the current goroutine's id out of its stack header,
+// "goroutine 123 [running]:". It is only meant for debug tracing.
+func goid() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type recordingLogger struct {
+	mu     sync.Mutex
+	events []string
+	goids  map[uint64]bool
+}
+
+func (l *recordingLogger) Lock(op string, goid uint64)   { l.record("lock "+op, goid) }
+func (l *recordingLogger) Unlock(op string, goid uint64) { l.record("unlock "+op, goid) }
+
+func (l *recordingLogger) record(event string, goid uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	if l.goids == nil {
+		l.goids = make(map[uint64]bool)
+	}
+	l.goids[goid] = true
+}
+
+func TestLoggerTracesIncAndGet(t *testing.T) {
+	p := newPost(0, 0)
+	l := &recordingLogger{}
+	p.logger = l
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go p.inc(&wg)
+	wg.Wait()
+	p.get()
+
+	want := []string{"lock inc", "unlock inc", "lock get", "unlock get"}
+	if fmt.Sprint(l.events) != fmt.Sprint(want) {
+		t.Fatalf("events = %q, want %q", l.events, want)
+	}
+	if len(l.goids) != 2 || l.goids[0] {
+		t.Fatalf("goroutine ids = %v, want two distinct non-zero ids", l.goids)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+func LoadPost(path string) (*post, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var v PostView
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return newPost(v.Views, 0), nil
+}
+
+// Save writes the post to a temp file next to path and renames it into
+// place, so readers never see a partial file. Saves of the same post run
+// one at a time, so the file always ends up holding the latest snapshot.
+func (p *post) Save(path string) error {
+	p.saveMu.Lock()
+	defer p.saveMu.Unlock()
+
+	b, err := json.Marshal(p.View())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "post.json")
+	p := newPost(0, 0)
+	for range 42 {
+		p.Inc()
+	}
+
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPost(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Get(); got != 42 {
+		t.Fatalf("loaded views = %d, want 42", got)
+	}
+}
+
+func TestConcurrentSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "post.json")
+	p := newPost(0, 0)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Inc()
+			if err := p.Save(path); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := LoadPost(path)
+	if err != nil {
+		t.Fatalf("LoadPost after concurrent saves: %v", err)
+	}
+	if got := loaded.Get(); got != 20 {
+		t.Fatalf("loaded views = %d, want 20", got)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp-*")); len(tmp) != 0 {
+		t.Fatalf("temp files left behind: %v", tmp)
+	}
+}
+
+func TestLoadPostMissing(t *testing.T) {
+	if _, err := LoadPost(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("LoadPost on a missing file succeeded")
+	}
+}
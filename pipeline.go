@@ -0,0 +1,48 @@
+package main
+
+import "sync"
+
+// Stage transforms IDs read from its input. Fn returns false to drop an ID.
+// Workers goroutines run Fn concurrently, so order is only kept when
+// Workers is 1. Output is buffered by Buffer; a full buffer blocks the
+// stage, which in turn blocks whatever feeds it.
+type Stage struct {
+	Fn      func(id string) (string, bool)
+	Workers int
+	Buffer  int
+}
+
+func (s Stage) run(in <-chan string) <-chan string {
+	var wg sync.WaitGroup
+	out := make(chan string, s.Buffer)
+
+	for range max(s.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range in {
+				if v, ok := s.Fn(id); ok {
+					out <- v
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// Pipeline chains stages so each one's output feeds the next. The returned
+// channel closes after in is closed and every stage has drained.
+func Pipeline(stages ...Stage) func(in <-chan string) <-chan string {
+	return func(in <-chan string) <-chan string {
+		for _, s := range stages {
+			in = s.run(in)
+		}
+		return in
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPipelineFilterAndIncrement(t *testing.T) {
+	p := newPost(0, 0)
+	run := Pipeline(
+		Stage{Workers: 4, Buffer: 1, Fn: func(id string) (string, bool) {
+			n, err := strconv.Atoi(id)
+			return id, err == nil && n%2 == 0
+		}},
+		Stage{Workers: 2, Fn: func(id string) (string, bool) {
+			p.Inc()
+			return id, true
+		}},
+	)
+
+	in := make(chan string)
+	go func() {
+		defer close(in)
+		for i := range 1000 {
+			in <- strconv.Itoa(i)
+		}
+	}()
+
+	out := 0
+	for range run(in) {
+		out++
+	}
+
+	if out != 500 {
+		t.Fatalf("pipeline emitted %d ids, want 500", out)
+	}
+	if got := p.Get(); got != 500 {
+		t.Fatalf("Get() = %d, want 500", got)
+	}
+}
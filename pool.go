@@ -0,0 +1,36 @@
+package main
+
+import "sync"
+
+type ViewWorkerPool struct {
+	store   *PostStore
+	workers int
+}
+
+func NewViewWorkerPool(store *PostStore, workers int) *ViewWorkerPool {
+	return &ViewWorkerPool{store: store, workers: max(workers, 1)}
+}
+
+// Run drains ids across the pool's workers and closes the returned channel
+// once ids is closed and every worker has finished.
+func (wp *ViewWorkerPool) Run(ids <-chan string) <-chan struct{} {
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for range wp.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				wp.store.IncViews(id)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	return done
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestViewWorkerPool(t *testing.T) {
+	var s PostStore
+	ids := make(chan string)
+	done := NewViewWorkerPool(&s, 8).Run(ids)
+
+	want := make(map[string]int)
+	for i := range 10000 {
+		id := fmt.Sprintf("post-%d", i%37)
+		want[id]++
+		ids <- id
+	}
+	close(ids)
+	<-done
+
+	for id, n := range want {
+		if got, _ := s.Views(id); got != n {
+			t.Fatalf("Views(%q) = %d, want %d", id, got, n)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	backoffBase = 100 * time.Microsecond
+	backoffMax  = 50 * time.Millisecond
+)
+
+type post struct {
+	Counter
+	readDelay time.Duration
+	logger    Logger
+
+	history history
+	cache   readCache
+	seen    keySet
+	saveMu  sync.Mutex // serializes Save from View to Rename
+
+	subs   map[uint64]func(newValue int)
+	nextID uint64
+	subsMu sync.Mutex
+}
+
+func newPost(views int, readDelay time.Duration) *post {
+	p := &post{readDelay: readDelay}
+	p.n = int64(views)
+	p.observe()
+	return p
+}
+
+func (p *post) inc(wg *sync.WaitGroup) {
+	defer wg.Done()
+	p.add(1)
+}
+
+func (p *post) Inc() {
+	p.add(1)
+}
+
+func (p *post) AddViews(n int, wg *sync.WaitGroup) {
+	defer wg.Done()
+	p.add(n)
+}
+
+// TryInc increments only if the write lock is free, and never blocks.
+func (p *post) TryInc() bool {
+	if !p.tryLock() {
+		return false
+	}
+	p.addAndUnlock(1)
+	return true
+}
+
+// IncWithBackoff retries TryInc up to maxRetries times, sleeping an
+// exponentially growing, jittered interval between attempts.
+func (p *post) IncWithBackoff(maxRetries int) bool {
+	delay := backoffBase
+	for attempt := 0; ; attempt++ {
+		if p.TryInc() {
+			return true
+		}
+		if attempt >= maxRetries {
+			return false
+		}
+		// math/rand/v2's top-level functions are safe for concurrent use.
+		time.Sleep(delay/2 + rand.N(delay/2+1))
+		delay = min(delay*2, backoffMax)
+	}
+}
+
+// IncContext increments once the write lock can be taken, polling with
+// TryLock so it can give up when ctx is done.
+func (p *post) IncContext(ctx context.Context) error {
+	if err := acquireContext(ctx, p.tryLock); err != nil {
+		return err
+	}
+	p.addAndUnlock(1)
+	return nil
+}
+
+func (p *post) Add(n int) {
+	p.add(n)
+}
+
+func (p *post) add(n int) int {
+	p.lock()
+	return p.addAndUnlock(n)
+}
+
+// Dec, Reset, CompareAndInc and TryAdd shadow Counter's versions so that
+// every change to views lands in the history and reaches subscribers.
+
+func (p *post) Dec() bool {
+	p.lock()
+	p.traceLock("dec")
+	ok := p.decLocked()
+	p.unlock("dec", ok)
+	return ok
+}
+
+func (p *post) Reset() {
+	p.lock()
+	p.traceLock("reset")
+	p.resetLocked()
+	p.unlock("reset", true)
+}
+
+func (p *post) CompareAndInc(expected int) (newValue int, ok bool) {
+	p.lock()
+	p.traceLock("inc")
+	newValue, ok = p.casLocked(expected)
+	p.unlock("inc", ok)
+	return newValue, ok
+}
+
+func (p *post) TryAdd(delta int64) (int64, error) {
+	p.lock()
+	p.traceLock("inc")
+	v, err := p.tryAddLocked(delta)
+	p.unlock("inc", err == nil)
+	return v, err
+}
+
+// addAndUnlock must be called with p.mu held for writing; it releases it
+// and returns the new value.
+func (p *post) addAndUnlock(n int) int {
+	p.traceLock("inc")
+	p.n = satAdd(p.n, int64(n))
+	p.changed()
+	return p.unlock("inc", true)
+}
+
+// unlock releases the write lock taken for op. If the op changed views, the
+// new value is recorded in the history and, once the lock is released,
+// passed to subscribers.
+func (p *post) unlock(op string, changed bool) int {
+	v := int(p.n)
+	if changed {
+		p.history.record(v)
+	}
+	p.mu.Unlock()
+	p.traceUnlock(op)
+
+	if changed {
+		p.notify(v)
+	}
+	return v
+}
+
+func (p *post) get() int {
+	p.rlock()
+	p.traceLock("get")
+	time.Sleep(p.readDelay)
+	v := int(p.n)
+	p.mu.RUnlock()
+	p.traceUnlock("get")
+	return v
+}
+
+// GetAsync performs a get in the background. The returned channel yields
+// the value once and is then closed.
+func (p *post) GetAsync() <-chan int {
+	ch := make(chan int, 1)
+	go func() {
+		ch <- p.get()
+		close(ch)
+	}()
+	return ch
+}
+
+// GetContext reads the value after readDelay, giving up as soon as ctx is
+// done, including while waiting for the read lock.
+func (p *post) GetContext(ctx context.Context) (int, error) {
+	if err := acquireContext(ctx, p.tryRLock); err != nil {
+		return 0, err
+	}
+	defer p.mu.RUnlock()
+
+	t := time.NewTimer(p.readDelay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-t.C:
+		return int(p.n), nil
+	}
+}
+
+func (p *post) Snapshot() (views int, at time.Time) {
+	p.rlock()
+	defer p.mu.RUnlock()
+	return int(p.n), time.Now()
+}
+
+// PostView is a plain copy of a post's state. It holds no lock or pointer,
+// so it can be shared between goroutines freely.
+type PostView struct {
+	Views int `json:"views"`
+}
+
+func (p *post) View() PostView {
+	p.rlock()
+	defer p.mu.RUnlock()
+	return PostView{Views: int(p.n)}
+}
+
+func (p *post) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.View())
+}
+
+// Subscribe registers fn to be called with the new value after every change.
+// Callbacks run outside the write lock.
+func (p *post) Subscribe(fn func(newValue int)) (unsubscribe func()) {
+	p.subsMu.Lock()
+	defer p.subsMu.Unlock()
+	if p.subs == nil {
+		p.subs = make(map[uint64]func(int))
+	}
+	id := p.nextID
+	p.nextID++
+	p.subs[id] = fn
+
+	return func() {
+		p.subsMu.Lock()
+		delete(p.subs, id)
+		p.subsMu.Unlock()
+	}
+}
+
+func (p *post) notify(v int) {
+	p.subsMu.Lock()
+	fns := make([]func(int), 0, len(p.subs))
+	for _, fn := range p.subs {
+		fns = append(fns, fn)
+	}
+	p.subsMu.Unlock()
+
+	for _, fn := range fns {
+		fn(v)
+	}
+}
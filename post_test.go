@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetAfterWait(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("get panicked: %v", r)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	p := newPost(0, 0)
+
+	for range 2 {
+		wg.Add(1)
+		go p.inc(&wg)
+	}
+
+	wg.Wait()
+	if got := p.get(); got != 2 {
+		t.Fatalf("get() = %d, want 2", got)
+	}
+}
+
+func TestGetContextSuccess(t *testing.T) {
+	p := newPost(3, 10*time.Millisecond)
+
+	got, err := p.GetContext(context.Background())
+	if err != nil || got != 3 {
+		t.Fatalf("GetContext() = %d, %v; want 3, nil", got, err)
+	}
+}
+
+func TestGetContextTimeout(t *testing.T) {
+	p := newPost(3, time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := p.GetContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("GetContext() error = %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("GetContext() returned after %v, want ~20ms", d)
+	}
+
+	// The read lock must have been released.
+	if !p.TryInc() {
+		t.Fatal("TryInc() failed after GetContext timed out")
+	}
+}
+
+func TestGetContextWriterHoldsLock(t *testing.T) {
+	p := newPost(0, 0)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := p.GetContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("GetContext() error = %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("GetContext() returned after %v, want ~20ms", d)
+	}
+}
+
+// lockCounter is a Logger that counts write-lock acquisitions.
+type lockCounter struct {
+	locks atomic.Int32
+}
+
+func (l *lockCounter) Lock(string, uint64)   { l.locks.Add(1) }
+func (l *lockCounter) Unlock(string, uint64) {}
+
+func TestAddViews(t *testing.T) {
+	p := newPost(0, 0)
+	l := &lockCounter{}
+	p.logger = l
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go p.AddViews(100, &wg)
+	}
+	wg.Wait()
+
+	if got := p.Get(); got != 10000 {
+		t.Fatalf("Get() = %d, want 10000", got)
+	}
+	if n := l.locks.Load(); n != 100 {
+		t.Fatalf("%d lock acquisitions, want 100 (one per batch)", n)
+	}
+}
+
+// BenchmarkAddViews applies 10k events per op, one lock each versus batches
+// of 100, and reports lock acquisitions per op. TestAddViews checks that
+// each AddViews call takes the lock exactly once.
+func BenchmarkAddViews(b *testing.B) {
+	const events = 10000
+
+	for _, batch := range []int{1, 100} {
+		b.Run(fmt.Sprintf("batch=%d", batch), func(b *testing.B) {
+			p := newPost(0, 0)
+			var wg sync.WaitGroup
+			locks := 0
+
+			for b.Loop() {
+				for range events / batch {
+					wg.Add(1)
+					p.AddViews(batch, &wg)
+					locks++
+				}
+			}
+
+			b.ReportMetric(float64(locks)/float64(b.N), "locks/op")
+		})
+	}
+}
+
+func TestSnapshotConsistent(t *testing.T) {
+	p := newPost(0, 0)
+	stop := make(chan struct{})
+	var writers sync.WaitGroup
+
+	for range 4 {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					p.Inc()
+				}
+			}
+		}()
+	}
+
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			lastViews, lastAt := p.Snapshot()
+			for range 1000 {
+				views, at := p.Snapshot()
+				if at.Before(lastAt) || views < lastViews {
+					t.Errorf("snapshot (%d, %v) after (%d, %v)", views, at, lastViews, lastAt)
+					return
+				}
+				lastViews, lastAt = views, at
+			}
+		}()
+	}
+
+	readers.Wait()
+	close(stop)
+	writers.Wait()
+}
+
+func TestNewPostReadDelay(t *testing.T) {
+	p := newPost(7, 0)
+	if got := p.get(); got != 7 {
+		t.Fatalf("get() = %d, want 7", got)
+	}
+
+	p = newPost(7, 30*time.Millisecond)
+	start := time.Now()
+	p.get()
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Fatalf("get() returned after %v, want at least 30ms", d)
+	}
+}
+
+func TestMarshalJSONConcurrentInc(t *testing.T) {
+	p := newPost(0, 0)
+	var wg sync.WaitGroup
+
+	for range 100 {
+		wg.Add(2)
+		go p.inc(&wg)
+		go func() {
+			defer wg.Done()
+			if _, err := json.Marshal(p); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"views":100}` {
+		t.Fatalf("json.Marshal = %s, want {\"views\":100}", b)
+	}
+}
+
+func TestCounterOpsReachHistoryAndSubscribers(t *testing.T) {
+	p := newPost(0, 0)
+	var got []int
+	p.Subscribe(func(v int) { got = append(got, v) })
+
+	if _, ok := p.CompareAndInc(0); !ok {
+		t.Fatal("CompareAndInc(0) failed")
+	}
+	if _, ok := p.CompareAndInc(0); ok {
+		t.Fatal("CompareAndInc(0) succeeded on views=1")
+	}
+	p.Inc()
+	if _, err := p.TryAdd(3); err != nil {
+		t.Fatal(err)
+	}
+	p.Dec()
+	p.Reset()
+	p.Dec() // no-op at zero
+
+	want := []int{1, 2, 5, 4, 0}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("subscriber saw %v, want %v", got, want)
+	}
+	var hist []int
+	for _, s := range p.History() {
+		hist = append(hist, s.Views)
+	}
+	if fmt.Sprint(hist) != fmt.Sprint(want) {
+		t.Fatalf("history = %v, want %v", hist, want)
+	}
+}
+
+func TestSubscribeSeesEveryIncrement(t *testing.T) {
+	p := newPost(0, 0)
+	var mu sync.Mutex
+	seen := make(map[int]int)
+	unsubscribe := p.Subscribe(func(v int) {
+		mu.Lock()
+		seen[v]++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go p.inc(&wg)
+	}
+	wg.Wait()
+
+	if len(seen) != 100 {
+		t.Fatalf("subscriber saw %d distinct values, want 100", len(seen))
+	}
+	for v := 1; v <= 100; v++ {
+		if seen[v] != 1 {
+			t.Fatalf("value %d seen %d times, want 1", v, seen[v])
+		}
+	}
+
+	unsubscribe()
+	p.Inc()
+	if len(seen) != 100 {
+		t.Fatal("subscriber called after unsubscribe")
+	}
+}
+
+func TestTryIncLockHeld(t *testing.T) {
+	p := newPost(0, 0)
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		p.mu.Lock()
+		close(locked)
+		<-release
+		p.mu.Unlock()
+	}()
+	<-locked
+
+	start := time.Now()
+	if p.TryInc() {
+		t.Fatal("TryInc() = true while the lock was held")
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Fatalf("TryInc blocked for %v", d)
+	}
+
+	close(release)
+	for !p.TryInc() {
+		runtime.Gosched()
+	}
+	if got := p.Get(); got != 1 {
+		t.Fatalf("Get() = %d, want 1", got)
+	}
+}
+
+func TestIncContextBlockedByRead(t *testing.T) {
+	p := newPost(0, time.Second)
+	go p.get()
+	for p.mu.TryLock() {
+		p.mu.Unlock()
+		runtime.Gosched()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := p.IncContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("IncContext() = %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("IncContext() returned after %v, want ~20ms", d)
+	}
+
+	if err := newPost(0, 0).IncContext(context.Background()); err != nil {
+		t.Fatalf("IncContext() on a free lock = %v", err)
+	}
+}
+
+func TestPostViewSharedAcrossGoroutines(t *testing.T) {
+	p := newPost(0, 0)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				p.Inc()
+			}
+		}
+	}()
+
+	for range 10 {
+		v := p.View()
+		var readers sync.WaitGroup
+		for range 4 {
+			readers.Add(1)
+			go func() {
+				defer readers.Done()
+				if v.Views < 0 {
+					t.Error("negative views in PostView")
+				}
+			}()
+		}
+		readers.Wait()
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestGetAsync(t *testing.T) {
+	p := newPost(9, 10*time.Millisecond)
+	ch := p.GetAsync()
+
+	select {
+	case v, ok := <-ch:
+		if !ok || v != 9 {
+			t.Fatalf("<-GetAsync() = %d, %v; want 9, true", v, ok)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetAsync did not deliver within 1s")
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("GetAsync channel not closed after the value")
+	}
+}
+
+func TestIncWithBackoff(t *testing.T) {
+	p := newPost(0, 0)
+	p.mu.Lock()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		p.mu.Unlock()
+	}()
+
+	if !p.IncWithBackoff(20) {
+		t.Fatal("IncWithBackoff(20) gave up after the lock was released")
+	}
+	if got := p.Get(); got != 1 {
+		t.Fatalf("Get() = %d, want 1", got)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.IncWithBackoff(2) {
+		t.Fatal("IncWithBackoff(2) succeeded while the lock stayed held")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type RateLimitMode int
+
+const (
+	RateLimitBlock RateLimitMode = iota
+	RateLimitDrop
+)
+
+// RateLimitedPost caps increments at rate per second using a token bucket
+// that holds up to one second's worth of tokens. The post is kept in a
+// named field so that only the throttled Inc can change it.
+type RateLimitedPost struct {
+	p    *post
+	Mode RateLimitMode
+
+	rate   float64
+	tokens float64
+	last   time.Time
+	mu     sync.Mutex
+}
+
+func NewRateLimitedPost(rate int) *RateLimitedPost {
+	return &RateLimitedPost{
+		p:      newPost(0, 0),
+		rate:   float64(max(rate, 1)),
+		tokens: float64(max(rate, 1)),
+		last:   time.Now(),
+	}
+}
+
+// Inc reports whether the increment was applied. In RateLimitBlock mode it
+// waits for a token and always returns true; in RateLimitDrop mode it
+// returns false when the budget is exhausted.
+func (r *RateLimitedPost) Inc() bool {
+	for {
+		wait, ok := r.take()
+		if ok {
+			r.p.Inc()
+			return true
+		}
+		if r.Mode == RateLimitDrop {
+			return false
+		}
+		time.Sleep(wait)
+	}
+}
+
+func (r *RateLimitedPost) Get() int {
+	return r.p.Get()
+}
+
+func (r *RateLimitedPost) View() PostView {
+	return r.p.View()
+}
+
+func (r *RateLimitedPost) take() (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens = min(r.rate, r.tokens+now.Sub(r.last).Seconds()*r.rate)
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - r.tokens) / r.rate * float64(time.Second)), false
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedPostBlocks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("takes ~9s")
+	}
+
+	r := NewRateLimitedPost(100)
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				r.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The first 100 ride the initial burst; the other 900 cost 9s at 100/s.
+	if d := time.Since(start); d < 8500*time.Millisecond || d > 11*time.Second {
+		t.Fatalf("1000 increments took %v, want ~9s", d)
+	}
+	if got := r.Get(); got != 1000 {
+		t.Fatalf("Get() = %d, want 1000", got)
+	}
+}
+
+func TestRateLimitedPostDrops(t *testing.T) {
+	r := NewRateLimitedPost(100)
+	r.Mode = RateLimitDrop
+
+	applied := 0
+	for range 150 {
+		if r.Inc() {
+			applied++
+		}
+	}
+
+	// Only the burst fits; allow a token or two of refill while looping.
+	if applied < 100 || applied > 105 {
+		t.Fatalf("%d of 150 increments applied, want ~100", applied)
+	}
+	if got := r.Get(); got != applied {
+		t.Fatalf("Get() = %d, want %d", got, applied)
+	}
+}
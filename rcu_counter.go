@@ -0,0 +1,28 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RCUCounter gives wait-free reads: Get loads the current value through an
+// atomic pointer, while writers serialize on mu, build a new value and
+// swap it in.
+type RCUCounter struct {
+	v  atomic.Pointer[int]
+	mu sync.Mutex
+}
+
+func (c *RCUCounter) Inc() {
+	c.mu.Lock()
+	next := c.Get() + 1
+	c.v.Store(&next)
+	c.mu.Unlock()
+}
+
+func (c *RCUCounter) Get() int {
+	if p := c.v.Load(); p != nil {
+		return *p
+	}
+	return 0
+}
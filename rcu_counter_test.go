@@ -0,0 +1,76 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRCUCounterRace(t *testing.T) {
+	var c RCUCounter
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 500 {
+				c.Inc()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			last := 0
+			for range 500 {
+				v := c.Get()
+				if v < last {
+					t.Errorf("Get() went backwards: %d after %d", v, last)
+					return
+				}
+				last = v
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := c.Get(); got != 4000 {
+		t.Fatalf("Get() = %d, want 4000", got)
+	}
+}
+
+// BenchmarkRead runs 64 readers per CPU against one writer goroutine that
+// keeps incrementing. Run with -cpu=1,4,8.
+func BenchmarkRead(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		c    incGetter
+	}{
+		{"RCU", &RCUCounter{}},
+		{"RWMutex", newPost(0, 0)},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			stop := make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						bc.c.Inc()
+					}
+				}
+			}()
+
+			b.SetParallelism(64)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					bc.c.Get()
+				}
+			})
+
+			close(stop)
+			<-done
+		})
+	}
+}
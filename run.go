@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Run calls each fn in its own goroutine and blocks until all of them
+// return. A panic in any fn is recovered and reported in the returned error.
+func Run(fns ...func()) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(fns))
+
+	for i, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = call(i, fn)
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// RunBounded is like Run but uses a buffered channel as a semaphore so that
+// at most limit fns run at the same time.
+func RunBounded(limit int, fns ...func()) error {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(limit, 1))
+	errs := make([]error, len(fns))
+
+	for i, fn := range fns {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = call(i, fn)
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// RunWithTimeout runs fns concurrently and waits up to d for all of them.
+// On timeout it cancels the context passed to each fn and returns
+// context.DeadlineExceeded straight away; fns that ignore the context keep
+// running in the background until they return.
+func RunWithTimeout(d time.Duration, fns ...func(ctx context.Context)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(fns))
+
+	for i, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = call(i, func() { fn(ctx) })
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return errors.Join(errs...)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func call(i int, fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("run: fn %d panicked: %v", i, r)
+		}
+	}()
+	fn()
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunWaitsForAll(t *testing.T) {
+	var done atomic.Int32
+	fn := func() {
+		time.Sleep(10 * time.Millisecond)
+		done.Add(1)
+	}
+
+	if err := Run(fn, fn, fn, fn); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	if got := done.Load(); got != 4 {
+		t.Fatalf("%d fns finished before Run returned, want 4", got)
+	}
+}
+
+func TestRunRecoversPanic(t *testing.T) {
+	var done atomic.Int32
+	err := Run(
+		func() { done.Add(1) },
+		func() { panic("boom") },
+		func() { done.Add(1) },
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "fn 1 panicked: boom") {
+		t.Fatalf("Run() = %v, want fn 1 panic reported", err)
+	}
+	if got := done.Load(); got != 2 {
+		t.Fatalf("%d other fns finished, want 2", got)
+	}
+}
+
+func TestRunBoundedLimit(t *testing.T) {
+	var running, peak atomic.Int32
+	fn := func() {
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+	}
+
+	fns := make([]func(), 40)
+	for i := range fns {
+		fns[i] = fn
+	}
+	if err := RunBounded(3, fns...); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := peak.Load(); got > 3 || got < 1 {
+		t.Fatalf("peak concurrency = %d, want 1..3", got)
+	}
+	if got := running.Load(); got != 0 {
+		t.Fatalf("%d fns still running after RunBounded returned", got)
+	}
+}
+
+func TestRunWithTimeoutAllFinish(t *testing.T) {
+	var done atomic.Int32
+	fn := func(ctx context.Context) { done.Add(1) }
+
+	if err := RunWithTimeout(time.Second, fn, fn, fn); err != nil {
+		t.Fatalf("RunWithTimeout() = %v", err)
+	}
+	if got := done.Load(); got != 3 {
+		t.Fatalf("%d fns finished, want 3", got)
+	}
+}
+
+func TestRunWithTimeoutExpires(t *testing.T) {
+	cancelled := make(chan struct{})
+	ignored := make(chan struct{})
+	release := make(chan struct{})
+
+	start := time.Now()
+	err := RunWithTimeout(20*time.Millisecond,
+		func(ctx context.Context) {
+			<-ctx.Done()
+			close(cancelled)
+		},
+		func(ctx context.Context) {
+			<-release
+			close(ignored)
+		},
+	)
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("RunWithTimeout() = %v, want DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("RunWithTimeout returned after %v, want ~20ms", d)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("context-aware fn was not cancelled")
+	}
+
+	// The fn that ignores its context still runs to completion.
+	close(release)
+	<-ignored
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("post is closed")
+
+// Server guards a post so that Close can stop new calls and wait for the
+// ones already in flight to finish.
+type Server struct {
+	p        *post
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+}
+
+func NewServer(p *post) *Server {
+	return &Server{p: p}
+}
+
+func (s *Server) Inc() error {
+	if err := s.begin(); err != nil {
+		return err
+	}
+	defer s.inflight.Done()
+	s.p.add(1)
+	return nil
+}
+
+func (s *Server) Get() (int, error) {
+	if err := s.begin(); err != nil {
+		return 0, err
+	}
+	defer s.inflight.Done()
+	return s.p.get(), nil
+}
+
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.inflight.Wait()
+	return nil
+}
+
+func (s *Server) begin() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	s.inflight.Add(1)
+	return nil
+}
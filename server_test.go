@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServerCloseWaitsForGet(t *testing.T) {
+	p := newPost(1, 50*time.Millisecond)
+	s := NewServer(p)
+
+	type result struct {
+		v   int
+		err error
+	}
+	got := make(chan result, 1)
+	go func() {
+		v, err := s.Get()
+		got <- result{v, err}
+	}()
+
+	// Wait until the get holds the read lock.
+	for p.mu.TryLock() {
+		p.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	select {
+	case r := <-got:
+		if r.err != nil || r.v != 1 {
+			t.Fatalf("Get() = %d, %v; want 1, nil", r.v, r.err)
+		}
+	default:
+		t.Fatal("Close returned before the in-flight get finished")
+	}
+
+	if err := s.Inc(); err != ErrClosed {
+		t.Fatalf("Inc() after Close = %v, want ErrClosed", err)
+	}
+	if _, err := s.Get(); err != ErrClosed {
+		t.Fatalf("Get() after Close = %v, want ErrClosed", err)
+	}
+	if err := s.Close(); err != ErrClosed {
+		t.Fatalf("second Close() = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+type counterShard struct {
+	n  int
+	mu sync.RWMutex
+	_  [32]byte // keep neighbouring shards off the same cache line
+}
+
+// ShardedCounter spreads writes across independently locked shards,
+// picked at random so writers share no state until they reach a shard.
+// Get pays for it by summing every shard.
+type ShardedCounter struct {
+	shards []counterShard
+}
+
+func NewShardedCounter(shards int) *ShardedCounter {
+	return &ShardedCounter{shards: make([]counterShard, max(shards, 1))}
+}
+
+func (c *ShardedCounter) Inc() {
+	s := &c.shards[rand.Uint64()%uint64(len(c.shards))]
+	s.mu.Lock()
+	s.n++
+	s.mu.Unlock()
+}
+
+func (c *ShardedCounter) Get() int {
+	total := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.RLock()
+		total += s.n
+		s.mu.RUnlock()
+	}
+	return total
+}
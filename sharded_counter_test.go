@@ -0,0 +1,43 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestShardedCounter(t *testing.T) {
+	c := NewShardedCounter(16)
+	var wg sync.WaitGroup
+
+	for range 1000 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Inc()
+		}()
+	}
+	wg.Wait()
+
+	if got := c.Get(); got != 1000 {
+		t.Fatalf("Get() = %d, want 1000", got)
+	}
+}
+
+// Run with -cpu=1,4,8,16 to compare write throughput as contention grows.
+func BenchmarkShardedCounterInc(b *testing.B) {
+	c := NewShardedCounter(16)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Inc()
+		}
+	})
+}
+
+func BenchmarkCounterInc(b *testing.B) {
+	var c Counter
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Inc()
+		}
+	})
+}
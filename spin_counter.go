@@ -0,0 +1,44 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+)
+
+const defaultSpins = 4
+
+// SpinCounter tries TryLock up to Spins times, yielding between attempts,
+// before parking on Lock. For critical sections as short as an increment
+// this can beat going straight to the blocking path.
+type SpinCounter struct {
+	Spins int
+
+	n  int
+	mu sync.Mutex
+}
+
+func NewSpinCounter() *SpinCounter {
+	return &SpinCounter{Spins: defaultSpins}
+}
+
+func (c *SpinCounter) Inc() {
+	c.lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func (c *SpinCounter) Get() int {
+	c.lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+func (c *SpinCounter) lock() {
+	for range c.Spins {
+		if c.mu.TryLock() {
+			return
+		}
+		runtime.Gosched()
+	}
+	c.mu.Lock()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSpinCounter(t *testing.T) {
+	c := NewSpinCounter()
+	var wg sync.WaitGroup
+
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				c.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := c.Get(); got != 10000 {
+		t.Fatalf("Get() = %d, want 10000", got)
+	}
+}
+
+// Run with -cpu=2,4,8 to see whether spinning helps this workload.
+func BenchmarkSpinCounter(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		c    incGetter
+	}{
+		{"Mutex", &mutexPost{}},
+		{"Spin", NewSpinCounter()},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					bc.c.Inc()
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import "sync"
+
+// PostStore holds posts keyed by ID. Lock ordering: s.mu is only held
+// while reading or writing the map and is always released before a post's
+// own lock is taken, so the two locks are never held at the same time.
+type PostStore struct {
+	posts map[string]*post
+	mu    sync.RWMutex
+}
+
+func (s *PostStore) Add(id string) {
+	s.getOrCreate(id)
+}
+
+func (s *PostStore) IncViews(id string) {
+	s.getOrCreate(id).Inc()
+}
+
+func (s *PostStore) Views(id string) (int, bool) {
+	s.mu.RLock()
+	p, ok := s.posts[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		return 0, false
+	}
+	return p.Get(), true
+}
+
+// Collect returns a copy of every post's views keyed by ID. The post set is
+// captured under the store lock; each post is then read under its own lock.
+func (s *PostStore) Collect() map[string]float64 {
+	posts := s.snapshot()
+	out := make(map[string]float64, len(posts))
+	for id, p := range posts {
+		out[id] = float64(p.Get())
+	}
+	return out
+}
+
+// ForEach calls fn for every post present when it starts, stopping early if
+// fn returns false. The store lock is not held while fn runs, so fn may call
+// back into the store.
+func (s *PostStore) ForEach(fn func(id string, views int) bool) {
+	for id, p := range s.snapshot() {
+		if !fn(id, p.Get()) {
+			return
+		}
+	}
+}
+
+func (s *PostStore) snapshot() map[string]*post {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	posts := make(map[string]*post, len(s.posts))
+	for id, p := range s.posts {
+		posts[id] = p
+	}
+	return posts
+}
+
+func (s *PostStore) getOrCreate(id string) *post {
+	s.mu.RLock()
+	p, ok := s.posts[id]
+	s.mu.RUnlock()
+	if ok {
+		return p
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.posts[id]; ok {
+		return p
+	}
+	if s.posts == nil {
+		s.posts = make(map[string]*post)
+	}
+	p = newPost(0, 0)
+	s.posts[id] = p
+	return p
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPostStoreConcurrentIncViews(t *testing.T) {
+	var s PostStore
+	var wg sync.WaitGroup
+
+	ids := make([]string, 50)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("post-%d", i)
+	}
+
+	// Readers and Add calls run alongside the writers to exercise the
+	// store-then-post lock ordering.
+	for g := range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, id := range ids {
+				s.IncViews(id)
+				s.Views(ids[(g+1)%len(ids)])
+				s.Add(id)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		if got, ok := s.Views(id); !ok || got != 200 {
+			t.Fatalf("Views(%q) = %d, %v; want 200, true", id, got, ok)
+		}
+	}
+}
+
+func TestPostStoreUnknownID(t *testing.T) {
+	var s PostStore
+	if _, ok := s.Views("missing"); ok {
+		t.Fatal("Views on unknown id reported ok")
+	}
+
+	s.Add("new")
+	if got, ok := s.Views("new"); !ok || got != 0 {
+		t.Fatalf("Views(new) = %d, %v; want 0, true", got, ok)
+	}
+}
+
+func TestPostStoreCollect(t *testing.T) {
+	var s PostStore
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				s.IncViews(fmt.Sprintf("post-%d", (g+i)%10))
+			}
+		}()
+	}
+
+	var collector sync.WaitGroup
+	collector.Add(1)
+	go func() {
+		defer collector.Done()
+		last := 0.0
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			total := 0.0
+			for _, v := range s.Collect() {
+				total += v
+			}
+			if total < last || total > 4000 {
+				t.Errorf("Collect total %v after %v, want within [last, 4000]", total, last)
+				return
+			}
+			last = total
+		}
+	}()
+
+	wg.Wait()
+	close(stop)
+	collector.Wait()
+
+	m := s.Collect()
+	if len(m) != 10 {
+		t.Fatalf("Collect() has %d ids, want 10", len(m))
+	}
+	for id, v := range m {
+		if v != 400 {
+			t.Fatalf("Collect()[%q] = %v, want 400", id, v)
+		}
+	}
+
+	m["post-0"] = -1
+	if v, _ := s.Views("post-0"); v != 400 {
+		t.Fatal("mutating the Collect map changed the store")
+	}
+}
+
+func TestPostStoreForEach(t *testing.T) {
+	var s PostStore
+	for i := range 10 {
+		s.IncViews(fmt.Sprintf("post-%d", i))
+	}
+
+	visited := 0
+	s.ForEach(func(id string, views int) bool {
+		// Calling back into the store must not deadlock.
+		s.Add(id + "-new")
+		s.IncViews(id)
+		if views != 1 {
+			t.Errorf("ForEach saw %q with %d views, want 1", id, views)
+		}
+		visited++
+		return true
+	})
+	if visited != 10 {
+		t.Fatalf("ForEach visited %d posts, want the 10 present at start", visited)
+	}
+
+	visited = 0
+	s.ForEach(func(string, int) bool {
+		visited++
+		return visited < 3
+	})
+	if visited != 3 {
+		t.Fatalf("ForEach visited %d posts after returning false, want 3", visited)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"unsafe"
+)
+
+var (
+	ErrInsufficientViews = errors.New("source has fewer views than requested")
+	ErrNegativeTransfer  = errors.New("transfer amount must not be negative")
+)
+
+// Transfer moves n views from src to dst atomically. Both posts are locked
+// in address order so concurrent transfers in opposite directions can't
+// deadlock.
+func Transfer(src, dst *post, n int) error {
+	if n < 0 {
+		return ErrNegativeTransfer
+	}
+	if n == 0 {
+		return nil
+	}
+	if src == dst {
+		if src.Get() < n {
+			return ErrInsufficientViews
+		}
+		return nil
+	}
+
+	first, second := src, dst
+	if uintptr(unsafe.Pointer(second)) < uintptr(unsafe.Pointer(first)) {
+		first, second = second, first
+	}
+	first.lock()
+	first.traceLock("transfer")
+	second.lock()
+	second.traceLock("transfer")
+	release := func() {
+		second.mu.Unlock()
+		second.traceUnlock("transfer")
+		first.mu.Unlock()
+		first.traceUnlock("transfer")
+	}
+
+	if src.n < int64(n) {
+		release()
+		return ErrInsufficientViews
+	}
+	if dst.n > math.MaxInt64-int64(n) {
+		release()
+		return ErrOverflow
+	}
+	src.n -= int64(n)
+	dst.n += int64(n)
+	srcViews, dstViews := int(src.n), int(dst.n)
+	src.history.record(srcViews)
+	dst.history.record(dstViews)
+	src.changed()
+	dst.changed()
+	release()
+
+	src.notify(srcViews)
+	dst.notify(dstViews)
+	return nil
+}
+
+// Merge adds the views of every src into dst. Each post is locked on its
+// own, never two at once, so Merge can't deadlock; srcs are only read.
+func Merge(dst *post, srcs ...*post) {
+	sum := 0
+	for _, src := range srcs {
+		sum += src.Get()
+	}
+	dst.Add(sum)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestTransferConservesTotal(t *testing.T) {
+	a, b := newPost(1000, 0), newPost(1000, 0)
+	var wg sync.WaitGroup
+
+	for i := range 200 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Transfer(a, b, i%7)
+		}()
+		go func() {
+			defer wg.Done()
+			Transfer(b, a, i%5)
+		}()
+		// Observe both sides mid-flight; each Get is consistent on its own.
+		if a.Get() < 0 || b.Get() < 0 {
+			t.Fatal("views went negative")
+		}
+	}
+	wg.Wait()
+
+	if total := a.Get() + b.Get(); total != 2000 {
+		t.Fatalf("total = %d, want 2000", total)
+	}
+}
+
+func TestTransferErrors(t *testing.T) {
+	a, b := newPost(3, 0), newPost(0, 0)
+
+	if err := Transfer(a, b, 4); err != ErrInsufficientViews {
+		t.Fatalf("Transfer 4 of 3 = %v, want ErrInsufficientViews", err)
+	}
+	if err := Transfer(a, b, -5); err != ErrNegativeTransfer {
+		t.Fatalf("Transfer -5 = %v, want ErrNegativeTransfer", err)
+	}
+	if err := Transfer(a, b, 3); err != nil {
+		t.Fatalf("Transfer 3 of 3 = %v", err)
+	}
+	if a.Get() != 0 || b.Get() != 3 {
+		t.Fatalf("views = %d, %d; want 0, 3", a.Get(), b.Get())
+	}
+}
+
+func TestTransferNotifiesAndTracesBothPosts(t *testing.T) {
+	a, b := newPost(5, 0), newPost(1, 0)
+	var gotA, gotB []int
+	a.Subscribe(func(v int) { gotA = append(gotA, v) })
+	b.Subscribe(func(v int) { gotB = append(gotB, v) })
+	la, lb := &recordingLogger{}, &recordingLogger{}
+	a.logger, b.logger = la, lb
+
+	if err := Transfer(a, b, 0); err != nil {
+		t.Fatalf("Transfer 0 = %v", err)
+	}
+	if len(gotA) != 0 || len(gotB) != 0 || len(la.events) != 0 || len(lb.events) != 0 {
+		t.Fatal("Transfer 0 notified subscribers or took a lock")
+	}
+
+	if err := Transfer(a, b, 2); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(gotA) != "[3]" || fmt.Sprint(gotB) != "[3]" {
+		t.Fatalf("subscribers saw %v and %v, want [3] and [3]", gotA, gotB)
+	}
+	want := []string{"lock transfer", "unlock transfer"}
+	for _, l := range []*recordingLogger{la, lb} {
+		if fmt.Sprint(l.events) != fmt.Sprint(want) {
+			t.Fatalf("events = %v, want %v", l.events, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dst := newPost(5, 0)
+	srcs := []*post{newPost(1, 0), newPost(10, 0), newPost(100, 0)}
+
+	Merge(dst, srcs...)
+
+	if got := dst.Get(); got != 116 {
+		t.Fatalf("dst = %d, want 116", got)
+	}
+	for i, want := range []int{1, 10, 100} {
+		if got := srcs[i].Get(); got != want {
+			t.Fatalf("src %d = %d, want %d unchanged", i, got, want)
+		}
+	}
+}
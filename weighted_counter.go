@@ -0,0 +1,31 @@
+package main
+
+import "sync"
+
+// WeightedCounter sums fractional view weights. Float addition is not
+// associative, so concurrent Adds can leave the total differing by a few
+// ULPs depending on the order they land in; Count is always exact.
+type WeightedCounter struct {
+	total float64
+	count int
+	mu    sync.Mutex
+}
+
+func (c *WeightedCounter) Add(weight float64) {
+	c.mu.Lock()
+	c.total += weight
+	c.count++
+	c.mu.Unlock()
+}
+
+func (c *WeightedCounter) Get() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+func (c *WeightedCounter) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
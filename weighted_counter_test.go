@@ -0,0 +1,30 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestWeightedCounterConcurrentAdd(t *testing.T) {
+	var c WeightedCounter
+	var wg sync.WaitGroup
+
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				c.Add(0.1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := c.Count(); got != 10000 {
+		t.Fatalf("Count() = %d, want 10000", got)
+	}
+	if got := c.Get(); math.Abs(got-1000) > 1e-6 {
+		t.Fatalf("Get() = %v, want 1000 within 1e-6", got)
+	}
+}